@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+// Package clustermeshpb contains the generated client/server code for the
+// ClusterMeshSync gRPC service defined in clustermesh.proto. Regenerate
+// clustermesh.pb.go and clustermesh_grpc.pb.go after editing the .proto by
+// running `make generate-api` from the repository root, or directly via:
+//
+//go:generate protoc -I. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative clustermesh.proto
+package clustermeshpb
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package models
+
+// ClusterMeshStatus is the status of the ClusterMesh subsystem, as
+// returned by the /cluster/mesh/status API and surfaced by `cilium
+// status`.
+type ClusterMeshStatus struct {
+	// NumGlobalServices is the number of global services currently known.
+	NumGlobalServices int64 `json:"num-global-services,omitempty"`
+
+	// Clusters is the status of each remote cluster currently known.
+	Clusters []*RemoteCluster `json:"clusters,omitempty"`
+
+	// ClusterIDConflicts lists every ClusterID conflict detected between
+	// remote clusters sharing the same local kvstore.
+	ClusterIDConflicts []*ClusterIDConflict `json:"cluster-id-conflicts,omitempty"`
+}
+
+// ClusterIDConflict records that two clusters have been observed using the
+// same ClusterID, and which of the two is considered the winner (i.e., the
+// one whose claim was already present in the local kvstore).
+type ClusterIDConflict struct {
+	// ClusterID is the ClusterID both clusters claimed.
+	ClusterID int64 `json:"cluster-id,omitempty"`
+
+	// Winner is the name of the cluster whose claim was already present.
+	Winner string `json:"winner,omitempty"`
+
+	// Loser is the name of the cluster whose later, conflicting claim was
+	// rejected.
+	Loser string `json:"loser,omitempty"`
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package models
+
+// RemoteCluster is the status of a single remote cluster known to
+// ClusterMesh, as surfaced by the /cluster/mesh/status API and `/healthz`.
+type RemoteCluster struct {
+	// Ready is true once the initial connection to the remote cluster has
+	// been established and all of its resources have been synchronized.
+	Ready bool `json:"ready,omitempty"`
+
+	// Status holds the low-level connectivity/sync status of the remote.
+	Status *RemoteClusterStatus `json:"status,omitempty"`
+
+	// Degraded is true if the remote cluster's active health probing has
+	// observed enough consecutive failures to consider the connection
+	// unhealthy and trigger a reconnection.
+	Degraded bool `json:"degraded,omitempty"`
+
+	// ConsecutiveHealthFailures is the number of consecutive health probes
+	// that have failed since the last successful one, or since the remote
+	// cluster was last marked Degraded, whichever is more recent.
+	ConsecutiveHealthFailures int64 `json:"consecutive-health-failures,omitempty"`
+
+	// LastHealthProbeTime is when the most recent health probe completed,
+	// formatted as RFC3339, or empty if the remote cluster has never been
+	// probed.
+	LastHealthProbeTime string `json:"last-health-probe-time,omitempty"`
+
+	// LastHealthProbeLatencySeconds is how long the most recent health
+	// probe took, in seconds.
+	LastHealthProbeLatencySeconds float64 `json:"last-health-probe-latency-seconds,omitempty"`
+}
+
+// RemoteClusterStatus is the low-level connectivity/sync status of a
+// single remote cluster connection.
+type RemoteClusterStatus struct {
+	// NumNodes is the number of nodes synchronized from the remote cluster.
+	NumNodes int64 `json:"num-nodes,omitempty"`
+
+	// NumSharedServices is the number of global services synchronized from
+	// the remote cluster.
+	NumSharedServices int64 `json:"num-shared-services,omitempty"`
+
+	// NumIdentities is the number of identities synchronized from the
+	// remote cluster.
+	NumIdentities int64 `json:"num-identities,omitempty"`
+}
@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package clustermesh
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// clusterIDClaimPrefix is the kvstore prefix under which each cluster
+// publishes a claim for the ClusterID it is using, so that two remote
+// clusters picking the same ClusterID independently can be detected.
+const clusterIDClaimPrefix = "cilium/clustermesh/cluster-ids"
+
+const (
+	minClusterID uint32 = 1
+	maxClusterID uint32 = 255
+)
+
+// ClusterIDConflict records that two clusters have been observed using the
+// same ClusterID, and which of the two is considered the winner (i.e., the
+// one whose claim was already present in the local kvstore).
+type ClusterIDConflict struct {
+	ClusterID uint32
+	Winner    string
+	Loser     string
+}
+
+// idRange is an inclusive [min, max] range of ClusterIDs reserved for a
+// given tenant, so that a fleet operator can hand out non-overlapping
+// blocks of IDs to different administrative domains.
+type idRange struct {
+	min, max uint32
+}
+
+func (r idRange) contains(id uint32) bool {
+	return id >= r.min && id <= r.max
+}
+
+// ClusterMeshUsedIDs tracks which ClusterIDs are currently in use by the
+// remote clusters known to this ClusterMesh, reserves contiguous ranges of
+// ClusterIDs for specific tenants, auto-assigns a free ClusterID to a
+// joining cluster whose configuration omits one, and detects ClusterID
+// collisions across remote clusters sharing the same local kvstore.
+type ClusterMeshUsedIDs struct {
+	localCluster string
+	metrics      Metrics
+
+	mutex          lock.Mutex
+	usedClusterIDs map[uint32]string // ClusterID -> owning remote cluster name
+	ranges         map[string]idRange
+	conflicts      []ClusterIDConflict
+}
+
+func newClusterMeshUsedIDs(localCluster string, metrics Metrics) *ClusterMeshUsedIDs {
+	return &ClusterMeshUsedIDs{
+		localCluster:   localCluster,
+		metrics:        metrics,
+		usedClusterIDs: make(map[uint32]string),
+		ranges:         make(map[string]idRange),
+	}
+}
+
+// releaseClusterID releases a previously reserved ClusterID.
+func (cm *ClusterMeshUsedIDs) releaseClusterID(clusterID uint32) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	delete(cm.usedClusterIDs, clusterID)
+}
+
+// ReserveFor reserves the explicitly configured clusterID for name and
+// publishes a claim for it in the local kvstore, so that the same ID being
+// independently configured for another cluster can be detected. It is
+// idempotent: calling it again for a name that already owns clusterID (e.g.,
+// as part of a reconnection attempt) is a no-op.
+func (cm *ClusterMeshUsedIDs) ReserveFor(ctx context.Context, clusterID uint32, name string) error {
+	cm.mutex.Lock()
+	if owner, ok := cm.usedClusterIDs[clusterID]; ok {
+		cm.mutex.Unlock()
+		if owner == name {
+			return nil
+		}
+		return fmt.Errorf("clusterID %d is already used by %q", clusterID, owner)
+	}
+	cm.usedClusterIDs[clusterID] = name
+	cm.mutex.Unlock()
+
+	if err := cm.publishClaim(ctx, clusterID, name); err != nil {
+		cm.releaseClusterID(clusterID)
+		return err
+	}
+
+	return nil
+}
+
+// ReserveRange reserves the inclusive [min, max] range of ClusterIDs for a
+// given tenant, so that Allocate only ever hands out IDs outside of ranges
+// reserved for other tenants.
+func (cm *ClusterMeshUsedIDs) ReserveRange(tenant string, min, max uint32) error {
+	if min == 0 || max < min {
+		return fmt.Errorf("invalid ClusterID range [%d, %d]", min, max)
+	}
+
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	r := idRange{min: min, max: max}
+	for other, existing := range cm.ranges {
+		if other == tenant {
+			continue
+		}
+		if existing.contains(r.min) || existing.contains(r.max) || r.contains(existing.min) {
+			return fmt.Errorf("ClusterID range [%d, %d] overlaps with range already reserved for tenant %q", min, max, other)
+		}
+	}
+
+	cm.ranges[tenant] = r
+	return nil
+}
+
+// Allocate assigns a free ClusterID to name, restricting the search to the
+// range reserved for tenant if one was reserved via ReserveRange, and
+// publishes a claim for it in the local kvstore so that the same ID being
+// independently picked by another cluster can be detected. Allocate is
+// idempotent: calling it again for a name that already owns an ID (e.g., as
+// part of a reconnection attempt) returns the same ID rather than handing
+// out a new one.
+func (cm *ClusterMeshUsedIDs) Allocate(ctx context.Context, tenant, name string) (uint32, error) {
+	cm.mutex.Lock()
+
+	for id, owner := range cm.usedClusterIDs {
+		if owner == name {
+			cm.mutex.Unlock()
+			return id, nil
+		}
+	}
+
+	lo, hi := minClusterID, maxClusterID
+	if r, ok := cm.ranges[tenant]; ok {
+		lo, hi = r.min, r.max
+	}
+
+	var id uint32
+	found := false
+	for candidate := lo; candidate <= hi; candidate++ {
+		reservedForOther := false
+		for other, r := range cm.ranges {
+			if other != tenant && r.contains(candidate) {
+				reservedForOther = true
+				break
+			}
+		}
+		if _, used := cm.usedClusterIDs[candidate]; !used && !reservedForOther {
+			id = candidate
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		cm.mutex.Unlock()
+		return 0, fmt.Errorf("no free ClusterID available in range [%d, %d] for tenant %q", lo, hi, tenant)
+	}
+
+	cm.usedClusterIDs[id] = name
+	cm.mutex.Unlock()
+
+	if err := cm.publishClaim(ctx, id, name); err != nil {
+		cm.releaseClusterID(id)
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Release releases the ClusterID allocated to name, if any, and withdraws
+// its claim from the local kvstore.
+func (cm *ClusterMeshUsedIDs) Release(ctx context.Context, name string) {
+	cm.mutex.Lock()
+	var id uint32
+	found := false
+	for cid, owner := range cm.usedClusterIDs {
+		if owner == name {
+			id, found = cid, true
+			break
+		}
+	}
+	if found {
+		delete(cm.usedClusterIDs, id)
+	}
+	cm.mutex.Unlock()
+
+	if found {
+		cm.withdrawClaim(ctx, id)
+	}
+}
+
+// publishClaim writes a claim key for the given ClusterID into the local
+// kvstore and checks whether a conflicting claim, made by a different
+// cluster, already exists.
+func (cm *ClusterMeshUsedIDs) publishClaim(ctx context.Context, id uint32, name string) error {
+	key := fmt.Sprintf("%s/%d", clusterIDClaimPrefix, id)
+
+	existing, err := kvstore.Client().Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("unable to read ClusterID claim for %d: %w", id, err)
+	}
+
+	if existing != nil && string(existing) != name {
+		conflict := ClusterIDConflict{ClusterID: id, Winner: string(existing), Loser: name}
+		cm.recordConflict(conflict)
+		cm.metrics.ClusterIDConflicts.WithLabelValues(fmt.Sprintf("%d", id)).Inc()
+
+		log.WithFields(map[string]interface{}{
+			logfields.ClusterID: id,
+			"winner":            conflict.Winner,
+			"loser":             conflict.Loser,
+		}).Warning("Detected ClusterID conflict between remote clusters")
+
+		return fmt.Errorf("clusterID %d is already claimed by %q", id, existing)
+	}
+
+	if err := kvstore.Client().Update(ctx, key, []byte(name), false); err != nil {
+		return fmt.Errorf("unable to publish ClusterID claim for %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// withdrawClaim deletes the claim key for the given ClusterID from the
+// local kvstore.
+func (cm *ClusterMeshUsedIDs) withdrawClaim(ctx context.Context, id uint32) {
+	key := fmt.Sprintf("%s/%d", clusterIDClaimPrefix, id)
+	if err := kvstore.Client().Delete(ctx, key); err != nil {
+		log.WithError(err).WithField(logfields.ClusterID, id).
+			Warning("Unable to withdraw ClusterID claim")
+	}
+}
+
+func (cm *ClusterMeshUsedIDs) recordConflict(conflict ClusterIDConflict) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	cm.conflicts = append(cm.conflicts, conflict)
+}
+
+// Conflicts returns the list of ClusterID conflicts observed so far.
+func (cm *ClusterMeshUsedIDs) Conflicts() []ClusterIDConflict {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	return append([]ClusterIDConflict(nil), cm.conflicts...)
+}
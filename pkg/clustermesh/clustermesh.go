@@ -6,7 +6,7 @@ package clustermesh
 import (
 	"context"
 	"errors"
-	"fmt"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/allocator"
@@ -18,7 +18,6 @@ import (
 	"github.com/cilium/cilium/pkg/k8s"
 	"github.com/cilium/cilium/pkg/kvstore"
 	"github.com/cilium/cilium/pkg/kvstore/store"
-	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	nodeTypes "github.com/cilium/cilium/pkg/node/types"
@@ -62,6 +61,13 @@ type Configuration struct {
 	// ServiceIPGetter, if not nil, is used to create a custom dialer for service resolution.
 	ServiceIPGetter k8s.ServiceIPGetter
 
+	// GlobalServiceFailbackDebounce is the minimum time a higher-priority
+	// cluster must keep reporting ready backends before the "failover"
+	// global service merging policy fails back to it. Zero disables
+	// debouncing and fails back as soon as the higher-priority cluster is
+	// observed ready.
+	GlobalServiceFailbackDebounce time.Duration `mapstructure:"clustermesh-global-service-failback-debounce"`
+
 	Metrics         Metrics
 	InternalMetrics internal.Metrics
 }
@@ -94,42 +100,14 @@ type ClusterMesh struct {
 	// is protected by its own mutex inside the structure.
 	globalServices *globalServiceCache
 
+	// policy resolves, per global service, which remote clusters'
+	// backends are currently selected by its configured ServicePolicy.
+	policy *PolicyEvaluator
+
 	// nodeName is the name of the local node. This is used for logging and metrics
 	nodeName string
 }
 
-type ClusterMeshUsedIDs struct {
-	usedClusterIDs      map[uint32]struct{}
-	usedClusterIDsMutex lock.Mutex
-}
-
-func newClusterMeshUsedIDs() *ClusterMeshUsedIDs {
-	return &ClusterMeshUsedIDs{
-		usedClusterIDs: make(map[uint32]struct{}),
-	}
-}
-
-func (cm *ClusterMeshUsedIDs) reserveClusterID(clusterID uint32) error {
-	cm.usedClusterIDsMutex.Lock()
-	defer cm.usedClusterIDsMutex.Unlock()
-
-	if _, ok := cm.usedClusterIDs[clusterID]; ok {
-		// ClusterID already used
-		return fmt.Errorf("clusterID %d is already used", clusterID)
-	}
-
-	cm.usedClusterIDs[clusterID] = struct{}{}
-
-	return nil
-}
-
-func (cm *ClusterMeshUsedIDs) releaseClusterID(clusterID uint32) {
-	cm.usedClusterIDsMutex.Lock()
-	defer cm.usedClusterIDsMutex.Unlock()
-
-	delete(cm.usedClusterIDs, clusterID)
-}
-
 // NewClusterMesh creates a new remote cluster cache based on the
 // provided configuration
 func NewClusterMesh(lifecycle hive.Lifecycle, c Configuration) *ClusterMesh {
@@ -140,11 +118,12 @@ func NewClusterMesh(lifecycle hive.Lifecycle, c Configuration) *ClusterMesh {
 	nodeName := nodeTypes.GetName()
 	cm := &ClusterMesh{
 		conf:     c,
-		usedIDs:  newClusterMeshUsedIDs(),
+		usedIDs:  newClusterMeshUsedIDs(c.ClusterName, c.Metrics),
 		nodeName: nodeName,
 		globalServices: newGlobalServiceCache(
 			c.Metrics.TotalGlobalServices.WithLabelValues(c.ClusterName, nodeName),
 		),
+		policy: NewPolicyEvaluator(c.ClusterName, c.GlobalServiceFailbackDebounce, c.Metrics),
 	}
 
 	cm.internal = internal.NewClusterMesh(internal.Configuration{
@@ -155,6 +134,17 @@ func NewClusterMesh(lifecycle hive.Lifecycle, c Configuration) *ClusterMesh {
 
 		NewRemoteCluster: cm.newRemoteCluster,
 
+		ReserveClusterID: func(ctx context.Context, name string, configuredID uint32) error {
+			if configuredID != 0 {
+				return cm.usedIDs.ReserveFor(ctx, configuredID, name)
+			}
+			_, err := cm.usedIDs.Allocate(ctx, "", name)
+			return err
+		},
+		ReleaseClusterID: func(ctx context.Context, name string) {
+			cm.usedIDs.Release(ctx, name)
+		},
+
 		NodeName: nodeName,
 		Metrics:  c.InternalMetrics,
 	})
@@ -226,7 +216,7 @@ func (cm *ClusterMesh) IPIdentitiesSynced(ctx context.Context) error {
 
 func (cm *ClusterMesh) synced(ctx context.Context, toWaitFn func(*remoteCluster) SyncedWaitFn) error {
 	waiters := make([]SyncedWaitFn, 0)
-	cm.internal.ForEachRemoteCluster(func(rci internal.RemoteCluster) error {
+	cm.internal.ForEachRemoteCluster(func(_ string, rci internal.RemoteCluster) error {
 		rc := rci.(*remoteCluster)
 		waiters = append(waiters, toWaitFn(rc))
 		return nil
@@ -244,17 +234,62 @@ func (cm *ClusterMesh) synced(ctx context.Context, toWaitFn func(*remoteCluster)
 	return nil
 }
 
+// GlobalServicePolicy returns the merging policy currently in effect for
+// the named global service, and the remote clusters currently selected as
+// its backend sources, or false if no policy decision has been recorded
+// for it yet (e.g., it is not a known global service). No API endpoint in
+// this package serves it.
+func (cm *ClusterMesh) GlobalServicePolicy(namespace, name string) (PolicyDecision, bool) {
+	return cm.policy.Decision(ServiceID{Namespace: namespace, Name: name})
+}
+
+// UpdateGlobalServicePolicy (re-)evaluates the merging policy for the given
+// global service against its current per-cluster ready backend counts, and
+// caches the resulting PolicyDecision so it is immediately visible through
+// GlobalServicePolicy. It must be called by the owner of globalServices
+// (i.e. the ServiceMerger implementation) every time backendsByCluster
+// changes for a global service, before that merged backend list is pushed
+// to the datapath, so that the configured ServicePolicy actually takes
+// effect instead of every ready backend always being included.
+func (cm *ClusterMesh) UpdateGlobalServicePolicy(namespace, name string, annotations map[string]string, backendsByCluster map[string]int) PolicyDecision {
+	id := ServiceID{Namespace: namespace, Name: name}
+	policy := ParseServicePolicy(annotations)
+	weights := ParseClusterWeights(annotations)
+	priority := ParseFailoverPriority(annotations)
+
+	return cm.policy.Evaluate(id, policy, backendsByCluster, weights, priority)
+}
+
 // Status returns the status of the ClusterMesh subsystem
 func (cm *ClusterMesh) Status() (status *models.ClusterMeshStatus) {
 	status = &models.ClusterMeshStatus{
 		NumGlobalServices: int64(cm.globalServices.size()),
 	}
 
-	cm.internal.ForEachRemoteCluster(func(rci internal.RemoteCluster) error {
+	cm.internal.ForEachRemoteCluster(func(name string, rci internal.RemoteCluster) error {
 		rc := rci.(*remoteCluster)
-		status.Clusters = append(status.Clusters, rc.Status())
+		clusterStatus := rc.Status()
+
+		if health, ok := cm.internal.RemoteClusterHealth(name); ok {
+			clusterStatus.Degraded = health.Degraded
+			clusterStatus.ConsecutiveHealthFailures = int64(health.ConsecutiveFailures)
+			clusterStatus.LastHealthProbeLatencySeconds = health.LastProbeLatency.Seconds()
+			if !health.LastProbeTime.IsZero() {
+				clusterStatus.LastHealthProbeTime = health.LastProbeTime.Format(time.RFC3339)
+			}
+		}
+
+		status.Clusters = append(status.Clusters, clusterStatus)
 		return nil
 	})
 
+	for _, conflict := range cm.usedIDs.Conflicts() {
+		status.ClusterIDConflicts = append(status.ClusterIDConflicts, &models.ClusterIDConflict{
+			ClusterID: int64(conflict.ClusterID),
+			Winner:    conflict.Winner,
+			Loser:     conflict.Loser,
+		})
+	}
+
 	return
 }
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cilium/cilium/pkg/clustermesh/types"
+)
+
+// remoteConfigHeader holds the fields common to every remote cluster
+// configuration file, parsed up front to select which RemoteBackend
+// implementation should be used before handing the file off to it.
+type remoteConfigHeader struct {
+	// Backend selects the RemoteBackend implementation to use. It
+	// defaults to RemoteBackendEtcd when omitted, preserving the
+	// behavior of a plain etcd configuration file.
+	Backend RemoteBackendKind `yaml:"backend"`
+
+	// GRPC holds the dialing parameters used when Backend is
+	// RemoteBackendGRPC.
+	GRPC grpcRemoteBackendConfig `yaml:"grpc"`
+}
+
+// newRemoteClusterBackend parses the configuration file for a remote
+// cluster, selects the RemoteBackend implementation it requests, and
+// establishes the corresponding connection.
+func newRemoteClusterBackend(name, configPath string) (types.CiliumClusterConfig, RemoteBackend, error) {
+	raw, err := os.ReadFile(configPath)
+	if err != nil {
+		return types.CiliumClusterConfig{}, nil, fmt.Errorf("unable to read remote cluster configuration for %s: %w", name, err)
+	}
+
+	var header remoteConfigHeader
+	if err := yaml.Unmarshal(raw, &header); err != nil {
+		return types.CiliumClusterConfig{}, nil, fmt.Errorf("unable to parse remote cluster configuration for %s: %w", name, err)
+	}
+
+	switch header.Backend {
+	case "", RemoteBackendEtcd:
+		return newEtcdRemoteCluster(name, configPath)
+	case RemoteBackendGRPC:
+		return newGRPCRemoteCluster(name, header.GRPC)
+	default:
+		return types.CiliumClusterConfig{}, nil, fmt.Errorf("remote cluster %s: unknown backend kind %q", name, header.Backend)
+	}
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/clustermesh/types"
+	"github.com/cilium/cilium/pkg/hive/cell"
+	"github.com/cilium/cilium/pkg/k8s"
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "clustermesh")
+
+// RemoteCluster is the interface implemented by the owner of the generic
+// ClusterMesh subsystem (i.e., the clustermesh package) to let it plug its
+// own logic to connect to, and watch, a given remote cluster.
+type RemoteCluster interface {
+	// Run connects to the remote cluster identified by the given backend and
+	// starts watching it, blocking until ctx is canceled. The ready channel
+	// is closed (or receives an error) once the connection is established.
+	Run(ctx context.Context, backend RemoteBackend, config types.CiliumClusterConfig, ready chan<- error)
+
+	// Stop releases all the resources associated with the remote cluster
+	// connection, without removing the remote cluster entirely (i.e., a
+	// subsequent Run call may be issued to reconnect).
+	Stop()
+
+	// Remove releases any resource which is not automatically released by
+	// Stop (e.g., reserved ClusterIDs), as the remote cluster is being
+	// permanently removed.
+	Remove()
+
+	// Status returns the current status of the remote cluster.
+	Status() *models.RemoteCluster
+}
+
+// StatusFunc is the type of a function returning the status of a remote
+// cluster, to be embedded inside the overall ClusterMesh status.
+type StatusFunc func() *models.RemoteClusterStatus
+
+// NewRemoteClusterFunc is the function used to create a new RemoteCluster
+// instance, given its name and a function returning its current status.
+type NewRemoteClusterFunc func(name string, status StatusFunc) RemoteCluster
+
+// Config is the configuration of the generic ClusterMesh subsystem.
+type Config struct {
+	// ClusterMeshConfig is the path to the directory containing one
+	// configuration file for each remote cluster to watch: an etcd
+	// configuration file, or one whose "backend" field selects another
+	// RemoteBackend.
+	ClusterMeshConfig string `mapstructure:"clustermesh-config"`
+
+	// ClusterHealthCheckInterval is the interval at which each remote
+	// cluster is actively health-probed.
+	ClusterHealthCheckInterval time.Duration `mapstructure:"clustermesh-health-check-interval"`
+
+	// ClusterHealthCheckTimeout bounds how long a single health probe is
+	// allowed to take before being considered a failure.
+	ClusterHealthCheckTimeout time.Duration `mapstructure:"clustermesh-health-check-timeout"`
+
+	// ClusterHealthCheckUnhealthyThreshold is the number of consecutive
+	// probe failures after which a remote cluster is marked Degraded and
+	// a reconnection is triggered.
+	ClusterHealthCheckUnhealthyThreshold int `mapstructure:"clustermesh-health-check-unhealthy-threshold"`
+}
+
+// Configuration is the configuration that must be provided to NewClusterMesh.
+type Configuration struct {
+	cell.In
+
+	Config
+	types.ClusterIDName
+
+	// NewRemoteCluster is the function used to create the concrete
+	// RemoteCluster instance backing each discovered remote.
+	NewRemoteCluster NewRemoteClusterFunc
+
+	// ReserveClusterID, if non-nil, is invoked once per remote cluster
+	// connection attempt, before it is started, so that the owner (i.e.,
+	// the clustermesh package) can validate (or, if configuredID is zero,
+	// auto-assign) the ClusterID used by that remote cluster. A connection
+	// attempt is aborted if it returns an error.
+	ReserveClusterID func(ctx context.Context, name string, configuredID uint32) error
+
+	// ReleaseClusterID, if non-nil, is invoked once a remote cluster is
+	// permanently removed, releasing any ClusterID reserved for it.
+	ReleaseClusterID func(ctx context.Context, name string)
+
+	// ClusterSizeDependantInterval allows to calculate intervals based on
+	// cluster size.
+	ClusterSizeDependantInterval kvstore.ClusterSizeDependantIntervalFunc
+
+	// ServiceIPGetter, if not nil, is used to create a custom dialer for
+	// service resolution.
+	ServiceIPGetter k8s.ServiceIPGetter
+
+	// NodeName is the name of the local node, used for logging and metrics.
+	NodeName string
+
+	Metrics Metrics
+}
+
+// ClusterMesh implements the generic logic to discover, connect to, and
+// watch an arbitrary number of remote clusters, delegating the actual
+// watching of each remote cluster to a RemoteCluster instance.
+type ClusterMesh struct {
+	// conf is the configuration, it is immutable after NewClusterMesh()
+	conf Configuration
+
+	// tracker serializes every add/remove/restart of a remote cluster
+	// behind a single lock, so that a cluster can never be simultaneously
+	// torn down and recreated.
+	tracker *remoteClusterTracker
+
+	// health holds the active health-probing state of every remote
+	// cluster currently or previously probed.
+	health *healthTracker
+}
+
+// NewClusterMesh creates a new generic ClusterMesh instance, responsible
+// for discovering remote clusters and driving their lifecycle.
+func NewClusterMesh(c Configuration) ClusterMesh {
+	return ClusterMesh{
+		conf:    c,
+		tracker: newRemoteClusterTracker(),
+		health:  newHealthTracker(),
+	}
+}
+
+// Start implements the hive.Lifecycle start hook, beginning to watch the
+// clustermesh configuration directory for remote cluster definitions.
+func (cm *ClusterMesh) Start(ctx cell.HookContext) error {
+	if cm.conf.ClusterMeshConfig == "" {
+		return nil
+	}
+
+	watcher, err := createConfigDirWatcher(cm.conf.ClusterMeshConfig, cm.onInsert, cm.onRemove)
+	if err != nil {
+		return fmt.Errorf("unable to watch clustermesh configuration directory %s: %w", cm.conf.ClusterMeshConfig, err)
+	}
+
+	return watcher.Watch()
+}
+
+// Stop implements the hive.Lifecycle stop hook, disconnecting from all the
+// currently known remote clusters.
+func (cm *ClusterMesh) Stop(ctx cell.HookContext) error {
+	cm.tracker.removeAll()
+	return nil
+}
+
+// NumReadyClusters returns the number of remote clusters to which a
+// connection has been established.
+func (cm *ClusterMesh) NumReadyClusters() int {
+	ready := 0
+	cm.tracker.forEach(func(entry *remoteClusterEntry) error {
+		if entry.Status().Ready {
+			ready++
+		}
+		return nil
+	})
+	return ready
+}
+
+// RemoteClusterHealth returns the active health-probing state of the named
+// remote cluster, and false if it has never been probed (e.g., it is
+// unknown, or health probing is disabled).
+func (cm *ClusterMesh) RemoteClusterHealth(name string) (RemoteClusterHealth, bool) {
+	return cm.health.get(name)
+}
+
+// ForEachRemoteCluster executes the provided function for each currently
+// known remote cluster, passing along its name so that the caller can
+// correlate it with other per-cluster state (e.g., RemoteClusterHealth),
+// stopping at (and returning) the first error.
+func (cm *ClusterMesh) ForEachRemoteCluster(fn func(name string, rc RemoteCluster) error) error {
+	return cm.tracker.forEach(func(entry *remoteClusterEntry) error {
+		return fn(entry.name, entry.RemoteCluster)
+	})
+}
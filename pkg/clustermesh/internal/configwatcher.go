@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/pkg/clustermesh/types"
+	"github.com/cilium/cilium/pkg/fswatcher"
+)
+
+// configDirWatcher watches a directory containing one configuration file
+// per remote cluster, invoking onInsert/onRemove as entries appear,
+// change, or disappear.
+type configDirWatcher struct {
+	dir       string
+	onInsert  func(name string, config types.CiliumClusterConfig, backend RemoteBackend, configPath string)
+	onRemove  func(name string)
+	fsWatcher *fswatcher.Watcher
+}
+
+// createConfigDirWatcher returns a configDirWatcher for the given
+// directory. Watch must be called to start watching for changes.
+func createConfigDirWatcher(dir string, onInsert func(name string, config types.CiliumClusterConfig, backend RemoteBackend, configPath string), onRemove func(name string)) (*configDirWatcher, error) {
+	return &configDirWatcher{
+		dir:      dir,
+		onInsert: onInsert,
+		onRemove: onRemove,
+	}, nil
+}
+
+// Watch performs an initial scan of the configuration directory and then
+// starts watching it for subsequent changes.
+func (w *configDirWatcher) Watch() error {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		w.handleUpsert(filepath.Join(w.dir, entry.Name()))
+	}
+
+	fw, err := fswatcher.New([]string{w.dir})
+	if err != nil {
+		return err
+	}
+	w.fsWatcher = fw
+
+	go w.loop()
+	return nil
+}
+
+func (w *configDirWatcher) loop() {
+	for {
+		select {
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			switch {
+			case event.Op&(fswatcher.Create|fswatcher.Write) != 0:
+				w.handleUpsert(event.Name)
+			case event.Op&fswatcher.Remove != 0:
+				w.onRemove(filepath.Base(event.Name))
+			}
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			log.WithError(err).Warning("Error while watching clustermesh configuration directory")
+		}
+	}
+}
+
+func (w *configDirWatcher) handleUpsert(path string) {
+	name := filepath.Base(path)
+
+	config, backend, err := newRemoteClusterBackend(name, path)
+	if err != nil {
+		log.WithError(err).WithField("name", name).
+			Warning("Unable to load remote cluster configuration")
+		return
+	}
+
+	w.onInsert(name, config, backend, path)
+}
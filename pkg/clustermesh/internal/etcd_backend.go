@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/clustermesh/types"
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/kvstore/store"
+)
+
+const (
+	nodeStorePrefix      = "cilium/state/nodes/v1"
+	serviceStorePrefix   = "cilium/state/services/v1"
+	ipCacheStorePrefix   = "cilium/state/ip/v1"
+	identityCachePrefix  = "cilium/state/identities/v1"
+	remoteHealthCheckKey = "cilium/cache/config"
+)
+
+// etcdRemoteBackend is the RemoteBackend implementation that syncs a
+// remote cluster directly off a kvstore.BackendOperations connection to
+// its etcd, i.e., the behavior ClusterMesh has always had. It is selected
+// whenever a remote's configuration file omits the "backend" field, or
+// sets it to "etcd".
+type etcdRemoteBackend struct {
+	name    string
+	backend kvstore.BackendOperations
+}
+
+// newEtcdRemoteCluster connects to the remote etcd identified by
+// configPath and retrieves the remote cluster's configuration, returning
+// a RemoteBackend that syncs directly off that connection.
+func newEtcdRemoteCluster(name, configPath string) (types.CiliumClusterConfig, RemoteBackend, error) {
+	opts := kvstore.ExtraOptions{
+		NoLockQuorumCheck: true,
+	}
+
+	backend, errChan := kvstore.NewClient(kvstore.EtcdBackendName, map[string]string{
+		kvstore.EtcdOptionConfig: configPath,
+	}, &opts)
+	if err := <-errChan; err != nil {
+		return types.CiliumClusterConfig{}, nil, fmt.Errorf("unable to connect to remote etcd for cluster %s: %w", name, err)
+	}
+
+	config, _, err := types.GetClusterConfig(name, "", backend)
+	if err != nil {
+		backend.Close()
+		return types.CiliumClusterConfig{}, nil, fmt.Errorf("unable to retrieve cluster configuration for %s: %w", name, err)
+	}
+
+	return config, &etcdRemoteBackend{name: name, backend: backend}, nil
+}
+
+func (b *etcdRemoteBackend) WatchNodes(ctx context.Context, _ string, newKey store.KeyCreator) (<-chan RemoteBackendEvent, error) {
+	return b.watch(ctx, nodeStorePrefix, newKey), nil
+}
+
+func (b *etcdRemoteBackend) WatchServices(ctx context.Context, _ string, newKey store.KeyCreator) (<-chan RemoteBackendEvent, error) {
+	return b.watch(ctx, serviceStorePrefix, newKey), nil
+}
+
+func (b *etcdRemoteBackend) WatchIPCache(ctx context.Context, _ string, newKey store.KeyCreator) (<-chan RemoteBackendEvent, error) {
+	return b.watch(ctx, ipCacheStorePrefix, newKey), nil
+}
+
+func (b *etcdRemoteBackend) WatchIdentities(ctx context.Context, _ string, newKey store.KeyCreator) (<-chan RemoteBackendEvent, error) {
+	return b.watch(ctx, identityCachePrefix, newKey), nil
+}
+
+// watch bridges the callback-based store.NewRestartableWatchStore API onto
+// the RemoteBackendEvent channel expected by RemoteBackend, so that
+// etcdRemoteBackend and grpcRemoteBackend can be driven uniformly by
+// whatever consumes a RemoteBackend.
+func (b *etcdRemoteBackend) watch(ctx context.Context, prefix string, newKey store.KeyCreator) <-chan RemoteBackendEvent {
+	out := make(chan RemoteBackendEvent)
+
+	rws := store.NewRestartableWatchStore(
+		b.name, newKey, &channelObserver{ctx: ctx, out: out},
+		store.RWSWithOnSyncCallback(func(ctx context.Context) {
+			select {
+			case out <- RemoteBackendEvent{Kind: RemoteBackendEventSynced}:
+			case <-ctx.Done():
+			}
+		}),
+	)
+
+	go func() {
+		defer close(out)
+		rws.Watch(ctx, b.backend, prefix)
+	}()
+
+	return out
+}
+
+func (b *etcdRemoteBackend) Ping(ctx context.Context) error {
+	_, err := b.backend.Get(ctx, remoteHealthCheckKey)
+	return err
+}
+
+func (b *etcdRemoteBackend) Close() error {
+	b.backend.Close()
+	return nil
+}
+
+// channelObserver adapts the store.Observer callback interface onto a
+// RemoteBackendEvent channel.
+type channelObserver struct {
+	ctx context.Context
+	out chan<- RemoteBackendEvent
+}
+
+func (o *channelObserver) OnUpdate(k store.Key) {
+	o.send(RemoteBackendEvent{Kind: RemoteBackendEventUpsert, Key: k})
+}
+
+func (o *channelObserver) OnDelete(k store.NamedKey) {
+	o.send(RemoteBackendEvent{Kind: RemoteBackendEventDelete, Key: k.(store.Key)})
+}
+
+func (o *channelObserver) send(ev RemoteBackendEvent) {
+	select {
+	case o.out <- ev:
+	case <-o.ctx.Done():
+	}
+}
@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/cilium/cilium/pkg/backoff"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// RemoteClusterHealth is a point-in-time snapshot of the active
+// health-probing state of a remote cluster, exposed through
+// ClusterMesh.RemoteClusterHealth.
+type RemoteClusterHealth struct {
+	// Degraded is true once ClusterHealthCheckUnhealthyThreshold
+	// consecutive probes have failed and a reconnection has been
+	// triggered, until the remote cluster recovers.
+	Degraded bool
+
+	// ConsecutiveFailures is the number of consecutive probes that have
+	// failed since the last successful one, or since the remote cluster was
+	// last marked Degraded, whichever is more recent: it is reset to zero
+	// whenever a health-triggered reconnection starts.
+	ConsecutiveFailures int
+
+	// ReconnectAttempts is the number of reconnection attempts made since
+	// the last successful probe, used to grow the backoff between
+	// successive restartDegraded attempts.
+	ReconnectAttempts int
+
+	// LastProbeTime is when the most recent probe completed.
+	LastProbeTime time.Time
+
+	// LastProbeLatency is how long the most recent probe took.
+	LastProbeLatency time.Duration
+}
+
+// healthTracker records the active health-probing state of every remote
+// cluster that has been probed at least once, keyed by cluster name. It
+// outlives any single prober goroutine, so that reconnect attempts keep
+// growing the backoff across repeated restartDegraded calls instead of
+// resetting every time a fresh prober is spawned.
+type healthTracker struct {
+	mutex lock.Mutex
+	state map[string]*RemoteClusterHealth
+}
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{
+		state: make(map[string]*RemoteClusterHealth),
+	}
+}
+
+// get returns a copy of the current health state of name, and false if it
+// has never been probed.
+func (h *healthTracker) get(name string) (RemoteClusterHealth, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state, ok := h.state[name]
+	if !ok {
+		return RemoteClusterHealth{}, false
+	}
+	return *state, true
+}
+
+// update applies fn to the health state of name, creating it if necessary,
+// and returns a copy of the updated state.
+func (h *healthTracker) update(name string, fn func(*RemoteClusterHealth)) RemoteClusterHealth {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state, ok := h.state[name]
+	if !ok {
+		state = &RemoteClusterHealth{}
+		h.state[name] = state
+	}
+	fn(state)
+	return *state
+}
+
+// remove forgets the health state of name, e.g. once the remote cluster has
+// been permanently removed.
+func (h *healthTracker) remove(name string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.state, name)
+}
+
+// runHealthProber periodically probes the given remote cluster's backend,
+// restarting the connection under an exponential backoff once
+// ClusterHealthCheckUnhealthyThreshold consecutive probes have failed.
+func (cm *ClusterMesh) runHealthProber(ctx context.Context, name string, backend RemoteBackend) {
+	scopedLog := log.WithField(logfields.ClusterName, name)
+
+	threshold := cm.conf.ClusterHealthCheckUnhealthyThreshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	timeout := cm.conf.ClusterHealthCheckTimeout
+	if timeout <= 0 {
+		timeout = cm.conf.ClusterHealthCheckInterval
+	}
+
+	ticker := time.NewTicker(cm.conf.ClusterHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := backend.Ping(probeCtx)
+		latency := time.Since(start)
+		cancel()
+
+		cm.conf.Metrics.RemoteClusterHealth.WithLabelValues(name).Set(boolToFloat(err == nil))
+		cm.conf.Metrics.RemoteClusterHealthProbeDuration.WithLabelValues(name).Observe(latency.Seconds())
+
+		if err == nil {
+			state := cm.health.update(name, func(s *RemoteClusterHealth) {
+				if s.ConsecutiveFailures > 0 {
+					scopedLog.Info("Remote cluster health probe recovered")
+				}
+				s.Degraded = false
+				s.ConsecutiveFailures = 0
+				s.ReconnectAttempts = 0
+				s.LastProbeTime = start
+				s.LastProbeLatency = latency
+			})
+			cm.conf.Metrics.RemoteClusterHealthConsecutiveFailures.WithLabelValues(name).Set(float64(state.ConsecutiveFailures))
+			continue
+		}
+
+		state := cm.health.update(name, func(s *RemoteClusterHealth) {
+			s.ConsecutiveFailures++
+			s.LastProbeTime = start
+			s.LastProbeLatency = latency
+		})
+		cm.conf.Metrics.RemoteClusterHealthConsecutiveFailures.WithLabelValues(name).Set(float64(state.ConsecutiveFailures))
+
+		scopedLog.WithError(err).WithField("consecutiveFailures", state.ConsecutiveFailures).
+			WithField("latency", latency).
+			Warning("Remote cluster health probe failed")
+
+		if state.ConsecutiveFailures < threshold {
+			continue
+		}
+
+		state = cm.health.update(name, func(s *RemoteClusterHealth) {
+			s.Degraded = true
+			s.ConsecutiveFailures = 0
+		})
+
+		scopedLog.Warning("Remote cluster marked as degraded, triggering reconnection")
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			go cm.restartDegraded(ctx, name, state.ReconnectAttempts)
+			return
+		}
+	}
+}
+
+// restartDegraded re-dials the backend for the given remote cluster and
+// reconnects it, retrying under a growing exponential backoff if the
+// backend cannot be re-established. It is a no-op if the remote cluster has
+// since been removed.
+func (cm *ClusterMesh) restartDegraded(ctx context.Context, name string, attempt int) {
+	scopedLog := log.WithField(logfields.ClusterName, name)
+	bo := backoff.Exponential{Min: time.Second, Max: 2 * time.Minute, Jitter: true}
+
+	if attempt > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bo.Duration(attempt)):
+		}
+	}
+
+	for {
+		entry, ok := cm.tracker.get(name)
+		if !ok {
+			return
+		}
+
+		config, backend, err := newRemoteClusterBackend(name, entry.lastConfigPath)
+		if err == nil {
+			cm.health.update(name, func(s *RemoteClusterHealth) {
+				s.ReconnectAttempts = attempt + 1
+			})
+			cm.connect(name, config, backend, entry.lastConfigPath)
+			return
+		}
+
+		state := cm.health.update(name, func(s *RemoteClusterHealth) {
+			s.ReconnectAttempts = attempt + 1
+		})
+		attempt = state.ReconnectAttempts
+
+		scopedLog.WithError(err).WithField("attempt", attempt).
+			Warning("Unable to re-establish connection to degraded remote cluster, retrying")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bo.Duration(attempt)):
+		}
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cilium/cilium/pkg/metrics/metric"
+)
+
+// Metrics holds the Prometheus metrics of the generic ClusterMesh
+// subsystem, i.e., the ones not specific to the concrete nodes/services/
+// identities watching logic implemented by the clustermesh package itself.
+type Metrics struct {
+	// RemoteClusterHealth reports, per remote cluster, whether the most
+	// recent active health probe succeeded (1) or failed (0).
+	RemoteClusterHealth metric.Vec[metric.Gauge]
+
+	// RemoteClusterHealthConsecutiveFailures reports, per remote cluster,
+	// the number of consecutive active health probes that have failed
+	// since the last successful one or the last health-triggered
+	// reconnection, whichever is more recent.
+	RemoteClusterHealthConsecutiveFailures metric.Vec[metric.Gauge]
+
+	// RemoteClusterHealthProbeDuration records the duration of each active
+	// health probe against a remote cluster.
+	RemoteClusterHealthProbeDuration metric.Vec[metric.Observer]
+}
+
+// NewMetrics returns a new Metrics instance using the default Prometheus
+// registry labels.
+func NewMetrics() Metrics {
+	return Metrics{
+		RemoteClusterHealth: metric.NewLegacyGaugeVec(prometheus.GaugeOpts{
+			Namespace: "cilium",
+			Subsystem: "clustermesh",
+			Name:      "remote_cluster_health",
+			Help:      "Whether the last active health probe against the remote cluster succeeded",
+		}, []string{"target_cluster"}),
+
+		RemoteClusterHealthConsecutiveFailures: metric.NewLegacyGaugeVec(prometheus.GaugeOpts{
+			Namespace: "cilium",
+			Subsystem: "clustermesh",
+			Name:      "remote_cluster_health_consecutive_failures",
+			Help:      "The number of consecutive active health probes that have failed against the remote cluster since the last successful one",
+		}, []string{"target_cluster"}),
+
+		RemoteClusterHealthProbeDuration: metric.NewLegacyHistogramVec(prometheus.HistogramOpts{
+			Namespace: "cilium",
+			Subsystem: "clustermesh",
+			Name:      "remote_cluster_health_probe_duration_seconds",
+			Help:      "Duration of the active health probe against the remote cluster",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"target_cluster"}),
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"context"
+
+	"github.com/cilium/cilium/pkg/kvstore/store"
+)
+
+// RemoteBackendKind identifies which concrete RemoteBackend implementation
+// should be used to sync a given remote cluster, as selected by the
+// "backend" field of its configuration file.
+type RemoteBackendKind string
+
+const (
+	// RemoteBackendEtcd drives the remote cluster's nodes/services/ipcache/
+	// identities directly off a kvstore.BackendOperations connection to the
+	// remote's etcd, exactly as ClusterMesh has always done.
+	RemoteBackendEtcd RemoteBackendKind = "etcd"
+
+	// RemoteBackendGRPC drives the remote cluster off a gRPC streaming sync
+	// source, e.g. a hub-and-spoke control plane fronting the remote's
+	// etcd instead of exposing it directly.
+	RemoteBackendGRPC RemoteBackendKind = "grpc"
+)
+
+// RemoteBackendEventKind distinguishes the three kinds of event a
+// RemoteBackend watch can emit for a given resource type.
+type RemoteBackendEventKind int
+
+const (
+	RemoteBackendEventUpsert RemoteBackendEventKind = iota
+	RemoteBackendEventDelete
+	RemoteBackendEventSynced
+)
+
+// RemoteBackendEvent is a single create/update/delete/synced notification
+// received from a RemoteBackend watch.
+type RemoteBackendEvent struct {
+	Kind RemoteBackendEventKind
+	Key  store.Key
+
+	// ResumeToken, when non-empty, is an opaque cursor that can be passed
+	// back to the corresponding Watch* call to resume the stream after
+	// this event without missing or re-delivering updates.
+	ResumeToken string
+}
+
+// RemoteBackend abstracts the source from which a remote cluster's nodes,
+// services, ipcache entries and identities are synced, so that
+// newRemoteCluster is not hard-wired to a direct etcd connection.
+type RemoteBackend interface {
+	// WatchNodes streams node create/update/delete events, decoding each
+	// payload using newKey.
+	WatchNodes(ctx context.Context, resumeToken string, newKey store.KeyCreator) (<-chan RemoteBackendEvent, error)
+
+	// WatchServices streams service create/update/delete events.
+	WatchServices(ctx context.Context, resumeToken string, newKey store.KeyCreator) (<-chan RemoteBackendEvent, error)
+
+	// WatchIPCache streams ipcache create/update/delete events.
+	WatchIPCache(ctx context.Context, resumeToken string, newKey store.KeyCreator) (<-chan RemoteBackendEvent, error)
+
+	// WatchIdentities streams identity create/update/delete events.
+	WatchIdentities(ctx context.Context, resumeToken string, newKey store.KeyCreator) (<-chan RemoteBackendEvent, error)
+
+	// Ping performs a lightweight liveness check against the remote sync
+	// source, used to drive the health-probing subsystem.
+	Ping(ctx context.Context) error
+
+	// Close releases any resource (e.g., the underlying gRPC connection)
+	// held by the backend.
+	Close() error
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"context"
+
+	"github.com/cilium/cilium/pkg/kvstore/store"
+)
+
+// WatchRemoteBackend drains one of backend's Watch* event streams (as
+// returned by WatchNodes/WatchServices/WatchIPCache/WatchIdentities),
+// replaying each event as the corresponding store.Observer callback. This
+// lets a RemoteCluster implementation consume a RemoteBackend exactly as it
+// previously consumed a direct kvstore connection through
+// store.NewRestartableWatchStore: OnUpdate/OnDelete per event, and onSync
+// once the backend reports the initial listing has been fully replayed. It
+// returns once events is closed or ctx is canceled.
+func WatchRemoteBackend(ctx context.Context, events <-chan RemoteBackendEvent, observer store.Observer, onSync func(ctx context.Context)) {
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+
+			switch ev.Kind {
+			case RemoteBackendEventUpsert:
+				observer.OnUpdate(ev.Key)
+			case RemoteBackendEventDelete:
+				if named, ok := ev.Key.(store.NamedKey); ok {
+					observer.OnDelete(named)
+				}
+			case RemoteBackendEventSynced:
+				if onSync != nil {
+					onSync(ctx)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cilium/cilium/pkg/kvstore/store"
+)
+
+// fakeKey is a minimal store.Key/store.NamedKey used to drive
+// WatchRemoteBackend in tests without a real kvstore-backed key type.
+type fakeKey string
+
+func (k fakeKey) GetKeyName() string { return string(k) }
+
+// recordingObserver records every OnUpdate/OnDelete/sync callback it
+// receives, so tests can assert WatchRemoteBackend replayed events in order.
+type recordingObserver struct {
+	updates []string
+	deletes []string
+	synced  chan struct{}
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{synced: make(chan struct{}, 1)}
+}
+
+func (o *recordingObserver) OnUpdate(k store.Key) {
+	o.updates = append(o.updates, k.(fakeKey).GetKeyName())
+}
+
+func (o *recordingObserver) OnDelete(k store.NamedKey) {
+	o.deletes = append(o.deletes, k.GetKeyName())
+}
+
+// TestWatchRemoteBackendReplaysEvents verifies that WatchRemoteBackend turns
+// a RemoteBackendEvent stream (as returned by a RemoteBackend's Watch*
+// methods) into the same OnUpdate/OnDelete/onSync callbacks a RemoteCluster
+// would previously have received directly from
+// store.NewRestartableWatchStore.
+func TestWatchRemoteBackendReplaysEvents(t *testing.T) {
+	events := make(chan RemoteBackendEvent, 3)
+	events <- RemoteBackendEvent{Kind: RemoteBackendEventUpsert, Key: fakeKey("node-a")}
+	events <- RemoteBackendEvent{Kind: RemoteBackendEventDelete, Key: fakeKey("node-b")}
+	events <- RemoteBackendEvent{Kind: RemoteBackendEventSynced}
+	close(events)
+
+	observer := newRecordingObserver()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		WatchRemoteBackend(ctx, events, observer, func(context.Context) { observer.synced <- struct{}{} })
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchRemoteBackend did not return after its event channel was closed")
+	}
+
+	require.Equal(t, []string{"node-a"}, observer.updates)
+	require.Equal(t, []string{"node-b"}, observer.deletes)
+
+	select {
+	case <-observer.synced:
+	default:
+		t.Fatal("onSync was not invoked for a RemoteBackendEventSynced event")
+	}
+}
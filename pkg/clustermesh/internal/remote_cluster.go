@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/backoff"
+	"github.com/cilium/cilium/pkg/clustermesh/types"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// onInsert is invoked whenever a new remote cluster configuration file
+// appears in the clustermesh configuration directory, or an existing one
+// is modified.
+func (cm *ClusterMesh) onInsert(name string, config types.CiliumClusterConfig, backend RemoteBackend, configPath string) {
+	cm.connect(name, config, backend, configPath)
+}
+
+// onRemove is invoked whenever a remote cluster configuration file is
+// deleted from the clustermesh configuration directory.
+func (cm *ClusterMesh) onRemove(name string) {
+	cm.tracker.remove(name)
+	cm.health.remove(name)
+
+	if cm.conf.ReleaseClusterID != nil {
+		cm.conf.ReleaseClusterID(context.Background(), name)
+	}
+}
+
+// connect installs a new RemoteCluster for name, replacing (and fully
+// draining) any previous one, and starts it along with its health prober.
+// The connection attempt is aborted if ReserveClusterID rejects it, e.g.
+// because the remote cluster's ClusterID is already claimed by another one.
+func (cm *ClusterMesh) connect(name string, config types.CiliumClusterConfig, backend RemoteBackend, configPath string) {
+	if cm.conf.ReserveClusterID != nil {
+		if err := cm.conf.ReserveClusterID(context.Background(), name, config.ID); err != nil {
+			log.WithError(err).WithField(logfields.ClusterName, name).
+				Warning("Rejecting connection to remote cluster due to ClusterID conflict")
+			return
+		}
+	}
+
+	status := func() *models.RemoteClusterStatus {
+		if entry, ok := cm.tracker.get(name); ok {
+			return entry.RemoteCluster.Status().Status
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rc := cm.conf.NewRemoteCluster(name, status)
+
+	entry := cm.tracker.install(name, func() *remoteClusterEntry {
+		return newRemoteClusterEntry(name, rc, cancel, config, backend, configPath)
+	})
+
+	var proberFn func(context.Context)
+	if cm.conf.ClusterHealthCheckInterval > 0 {
+		proberFn = func(ctx context.Context) { cm.runHealthProber(ctx, name, backend) }
+	}
+
+	ready := make(chan error, 1)
+	runEntry(ctx, entry, backend, config, ready, proberFn)
+
+	go cm.restartOnFailure(ctx, name, config, backend, configPath, ready)
+}
+
+// restartOnFailure waits for the initial connection attempt to complete
+// and, if it fails, re-dials the backend and reconnects the remote cluster
+// using an exponential, jittered backoff instead of retrying in a tight
+// loop against the same already-failed backend.
+func (cm *ClusterMesh) restartOnFailure(ctx context.Context, name string, config types.CiliumClusterConfig, backend RemoteBackend, configPath string, ready <-chan error) {
+	err := <-ready
+	if err == nil {
+		return
+	}
+
+	log.WithError(err).WithField(logfields.ClusterName, name).
+		Warning("Error while establishing connection to remote cluster")
+
+	bo := backoff.Exponential{Min: time.Second, Max: 2 * time.Minute, Jitter: true}
+	attempt := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bo.Duration(attempt)):
+		}
+
+		if _, ok := cm.tracker.get(name); !ok {
+			return
+		}
+
+		newConfig, newBackend, err := newRemoteClusterBackend(name, configPath)
+		if err != nil {
+			attempt++
+			log.WithError(err).WithField(logfields.ClusterName, name).WithField("attempt", attempt).
+				Warning("Unable to re-establish connection to remote cluster, retrying")
+			continue
+		}
+
+		cm.connect(name, newConfig, newBackend, configPath)
+		return
+	}
+}
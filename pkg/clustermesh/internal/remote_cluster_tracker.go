@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cilium/cilium/pkg/clustermesh/types"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// remoteClusterEntry wraps a RemoteCluster together with the bookkeeping
+// state required to run, restart, and fully drain it before it is replaced.
+type remoteClusterEntry struct {
+	RemoteCluster
+
+	name   string
+	cancel context.CancelFunc
+
+	// stopped is closed once every goroutine associated with this entry
+	// (the Run loop and, if any, the health prober) has returned. Nothing
+	// may install a new entry for the same cluster name until this channel
+	// is closed, guaranteeing that the old watch stores are never left
+	// running against a backend the new entry no longer knows about.
+	stopped chan struct{}
+
+	// lastConfig and lastBackend record the parameters used for the most
+	// recent connection attempt. lastConfigPath is the configuration file
+	// they were read from, so that a health-check triggered restart can
+	// re-dial a fresh backend instead of reusing the one whose probe failed.
+	lastConfig     types.CiliumClusterConfig
+	lastBackend    RemoteBackend
+	lastConfigPath string
+}
+
+// remoteClusterTracker tracks every remote cluster's lifecycle. Two
+// separate locks are used on purpose:
+//
+//   - mutex guards only the entries map itself, for plain lookups and
+//     snapshots, and is never held while invoking a RemoteCluster's
+//     methods or waiting on a channel.
+//   - lifecycle serializes install/remove/removeAll transitions, so that a
+//     cluster can never be simultaneously evicted and recreated, but is
+//     likewise never held while calling out to a RemoteCluster.
+//
+// This split matters because a RemoteCluster's Status() implementation is
+// free to call back into the tracker (e.g., the status closure built by
+// connect() calls get()); holding mutex across such a call, or across the
+// wait for an entry to drain, would deadlock against that callback.
+type remoteClusterTracker struct {
+	mutex   lock.Mutex
+	entries map[string]*remoteClusterEntry
+
+	lifecycle lock.Mutex
+}
+
+func newRemoteClusterTracker() *remoteClusterTracker {
+	return &remoteClusterTracker{
+		entries: make(map[string]*remoteClusterEntry),
+	}
+}
+
+// install replaces (or creates) the entry for name with the one returned by
+// build, waiting for any previous entry to fully drain first. build is
+// invoked with the lifecycle lock held, and must not block.
+func (t *remoteClusterTracker) install(name string, build func() *remoteClusterEntry) *remoteClusterEntry {
+	t.lifecycle.Lock()
+	defer t.lifecycle.Unlock()
+
+	t.evict(name)
+
+	entry := build()
+	t.storeEntry(name, entry)
+	return entry
+}
+
+// remove tears down and forgets the entry for name, if any, waiting for it
+// to fully drain before returning.
+func (t *remoteClusterTracker) remove(name string) {
+	t.lifecycle.Lock()
+	defer t.lifecycle.Unlock()
+
+	t.evict(name)
+}
+
+// removeAll tears down and forgets every tracked entry.
+func (t *remoteClusterTracker) removeAll() {
+	t.lifecycle.Lock()
+	defer t.lifecycle.Unlock()
+
+	for _, entry := range t.snapshot() {
+		t.evict(entry.name)
+	}
+}
+
+// evict cancels, stops, removes and drains the entry for name, if present.
+// The lifecycle lock must already be held. It deliberately releases the
+// map mutex before cancel()ing and waiting for the entry to drain, since
+// that wait (and Stop/Remove) may otherwise call back into the tracker.
+func (t *remoteClusterTracker) evict(name string) {
+	entry, ok := t.loadAndDeleteEntry(name)
+	if !ok {
+		return
+	}
+
+	entry.cancel()
+	<-entry.stopped
+
+	entry.Stop()
+	entry.Remove()
+
+	if entry.lastBackend != nil {
+		if err := entry.lastBackend.Close(); err != nil {
+			log.WithError(err).WithField(logfields.ClusterName, entry.name).
+				Warning("Error while closing remote cluster backend")
+		}
+	}
+}
+
+func (t *remoteClusterTracker) get(name string) (*remoteClusterEntry, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	entry, ok := t.entries[name]
+	return entry, ok
+}
+
+func (t *remoteClusterTracker) storeEntry(name string, entry *remoteClusterEntry) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.entries[name] = entry
+}
+
+func (t *remoteClusterTracker) loadAndDeleteEntry(name string) (*remoteClusterEntry, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	entry, ok := t.entries[name]
+	if ok {
+		delete(t.entries, name)
+	}
+	return entry, ok
+}
+
+// snapshot returns a point-in-time copy of every currently tracked entry.
+func (t *remoteClusterTracker) snapshot() []*remoteClusterEntry {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	entries := make([]*remoteClusterEntry, 0, len(t.entries))
+	for _, entry := range t.entries {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// forEach executes fn for every currently tracked entry, stopping at (and
+// returning) the first error. fn is invoked against a snapshot taken
+// without holding any tracker lock, so it is free to call back into the
+// tracker (e.g., via a RemoteCluster's Status method).
+func (t *remoteClusterTracker) forEach(fn func(*remoteClusterEntry) error) error {
+	for _, entry := range t.snapshot() {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newRemoteClusterEntry creates the bookkeeping entry for a newly connected
+// remote cluster; runEntry is responsible for actually starting it.
+func newRemoteClusterEntry(name string, rc RemoteCluster, cancel context.CancelFunc, config types.CiliumClusterConfig, backend RemoteBackend, configPath string) *remoteClusterEntry {
+	return &remoteClusterEntry{
+		RemoteCluster:  rc,
+		name:           name,
+		cancel:         cancel,
+		stopped:        make(chan struct{}),
+		lastConfig:     config,
+		lastBackend:    backend,
+		lastConfigPath: configPath,
+	}
+}
+
+// runEntry starts the goroutine running entry's RemoteCluster, plus the
+// health prober goroutine when proberFn is non-nil, and arranges for the
+// entry's stopped channel to be closed once they have all returned.
+func runEntry(ctx context.Context, entry *remoteClusterEntry, backend RemoteBackend, config types.CiliumClusterConfig, ready chan<- error, proberFn func(ctx context.Context)) {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		entry.Run(ctx, backend, config, ready)
+	}()
+
+	if proberFn != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			proberFn(ctx)
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(entry.stopped)
+	}()
+}
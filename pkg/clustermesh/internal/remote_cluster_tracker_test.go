@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package internal
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/clustermesh/types"
+	"github.com/cilium/cilium/pkg/kvstore/store"
+)
+
+// fakeBackend is a RemoteBackend that never actually watches anything, used
+// to assert that the tracker closes the backend it owns exactly once per
+// entry when that entry is evicted.
+type fakeBackend struct {
+	closes int32
+}
+
+func (b *fakeBackend) WatchNodes(context.Context, string, store.KeyCreator) (<-chan RemoteBackendEvent, error) {
+	return nil, nil
+}
+func (b *fakeBackend) WatchServices(context.Context, string, store.KeyCreator) (<-chan RemoteBackendEvent, error) {
+	return nil, nil
+}
+func (b *fakeBackend) WatchIPCache(context.Context, string, store.KeyCreator) (<-chan RemoteBackendEvent, error) {
+	return nil, nil
+}
+func (b *fakeBackend) WatchIdentities(context.Context, string, store.KeyCreator) (<-chan RemoteBackendEvent, error) {
+	return nil, nil
+}
+func (b *fakeBackend) Ping(context.Context) error { return nil }
+func (b *fakeBackend) Close() error {
+	atomic.AddInt32(&b.closes, 1)
+	return nil
+}
+
+// noopRemoteCluster is a minimal RemoteCluster used to drive the tracker's
+// bookkeeping in tests; Run blocks until its context is canceled, mimicking
+// a real watch loop without touching any backend.
+type noopRemoteCluster struct{}
+
+func (n *noopRemoteCluster) Run(ctx context.Context, _ RemoteBackend, _ types.CiliumClusterConfig, ready chan<- error) {
+	ready <- nil
+	<-ctx.Done()
+}
+func (n *noopRemoteCluster) Stop()   {}
+func (n *noopRemoteCluster) Remove() {}
+func (n *noopRemoteCluster) Status() *models.RemoteCluster {
+	return &models.RemoteCluster{Ready: true}
+}
+
+// statusCallbackRemoteCluster calls back into the tracker from its Status
+// method, mimicking the production status closure built by connect()
+// (cm.tracker.get(name)), which is invoked from within a RemoteCluster's
+// own Status() implementation.
+type statusCallbackRemoteCluster struct {
+	tracker *remoteClusterTracker
+	name    string
+}
+
+func (n *statusCallbackRemoteCluster) Run(ctx context.Context, _ RemoteBackend, _ types.CiliumClusterConfig, ready chan<- error) {
+	ready <- nil
+	<-ctx.Done()
+}
+func (n *statusCallbackRemoteCluster) Stop()   {}
+func (n *statusCallbackRemoteCluster) Remove() {}
+func (n *statusCallbackRemoteCluster) Status() *models.RemoteCluster {
+	n.tracker.get(n.name)
+	return &models.RemoteCluster{Ready: true}
+}
+
+// TestRemoteClusterTrackerStatusCallbackDoesNotDeadlock guards against
+// forEach (and thus NumReadyClusters/Status) holding a lock across a
+// RemoteCluster method call, since a RemoteCluster's Status() is free to
+// call back into the tracker via get().
+func TestRemoteClusterTrackerStatusCallbackDoesNotDeadlock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	tracker := newRemoteClusterTracker()
+	const clusterName = "status-cluster"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rc := &statusCallbackRemoteCluster{tracker: tracker, name: clusterName}
+
+	entry := tracker.install(clusterName, func() *remoteClusterEntry {
+		return newRemoteClusterEntry(clusterName, rc, cancel, types.CiliumClusterConfig{}, nil, "")
+	})
+
+	ready := make(chan error, 1)
+	runEntry(ctx, entry, nil, types.CiliumClusterConfig{}, ready, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tracker.forEach(func(e *remoteClusterEntry) error {
+			e.Status()
+			return nil
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("forEach deadlocked when Status called back into the tracker")
+	}
+
+	tracker.remove(clusterName)
+}
+
+// TestRemoteClusterTrackerEvictClosesBackend guards against the backend a
+// remote cluster connection owns (the etcd client / gRPC ClientConn created
+// by newRemoteClusterBackend) leaking whenever an entry is torn down,
+// whether by an explicit remove or by being replaced via install.
+func TestRemoteClusterTrackerEvictClosesBackend(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	tracker := newRemoteClusterTracker()
+	const clusterName = "evict-cluster"
+
+	removed := &fakeBackend{}
+	installChurning(tracker, clusterName, removed)
+	tracker.remove(clusterName)
+	require.EqualValues(t, 1, atomic.LoadInt32(&removed.closes), "backend must be closed when the entry is removed")
+
+	replaced := &fakeBackend{}
+	installChurning(tracker, clusterName, replaced)
+	replacement := &fakeBackend{}
+	installChurning(tracker, clusterName, replacement)
+	require.EqualValues(t, 1, atomic.LoadInt32(&replaced.closes), "backend must be closed when the entry is replaced")
+
+	tracker.remove(clusterName)
+	require.EqualValues(t, 1, atomic.LoadInt32(&replacement.closes), "backend must be closed when the final entry is removed")
+}
+
+func installChurning(tracker *remoteClusterTracker, name string, backend RemoteBackend) *remoteClusterEntry {
+	ctx, cancel := context.WithCancel(context.Background())
+	rc := &noopRemoteCluster{}
+
+	entry := tracker.install(name, func() *remoteClusterEntry {
+		return newRemoteClusterEntry(name, rc, cancel, types.CiliumClusterConfig{}, backend, "")
+	})
+
+	ready := make(chan error, 1)
+	runEntry(ctx, entry, backend, types.CiliumClusterConfig{}, ready, nil)
+	return entry
+}
+
+// TestRemoteClusterTrackerConcurrentChurn hammers add/remove/restart for
+// the same cluster name concurrently and asserts that no goroutines leak,
+// the stopped channel of every entry is closed exactly once, and the
+// tracker ends up with no residual entries.
+func TestRemoteClusterTrackerConcurrentChurn(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	tracker := newRemoteClusterTracker()
+	const clusterName = "churn-cluster"
+
+	var mu sync.Mutex
+	var backends []*fakeBackend
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			backend := &fakeBackend{}
+			mu.Lock()
+			backends = append(backends, backend)
+			mu.Unlock()
+
+			installChurning(tracker, clusterName, backend)
+			if i%2 == 0 {
+				tracker.remove(clusterName)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	tracker.removeAll()
+
+	_, ok := tracker.get(clusterName)
+	require.False(t, ok, "no entry should remain registered after removeAll")
+
+	for _, backend := range backends {
+		require.EqualValues(t, 1, atomic.LoadInt32(&backend.closes), "backend must be closed exactly once")
+	}
+}
+
+func TestRemoteClusterTrackerInstallDrainsPrevious(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	tracker := newRemoteClusterTracker()
+	const clusterName = "drain-cluster"
+
+	first := installChurning(tracker, clusterName, &fakeBackend{})
+	second := installChurning(tracker, clusterName, &fakeBackend{})
+
+	select {
+	case <-first.stopped:
+	default:
+		t.Fatal("previous entry should have been drained before the new one was installed")
+	}
+
+	tracker.remove(clusterName)
+
+	select {
+	case <-second.stopped:
+	default:
+		t.Fatal("current entry should be drained after remove")
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package clustermesh
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cilium/cilium/pkg/metrics/metric"
+)
+
+// Metrics holds the Prometheus metrics of the clustermesh package, i.e.,
+// the ones specific to the nodes/services/identities watching logic
+// implemented here, as opposed to the generic remote cluster lifecycle
+// metrics in internal.Metrics.
+type Metrics struct {
+	// TotalGlobalServices is the number of global services known to the
+	// local cluster.
+	TotalGlobalServices metric.Vec[metric.Gauge]
+
+	// TotalNodes is the number of nodes known about for each remote
+	// cluster.
+	TotalNodes metric.Vec[metric.Gauge]
+
+	// ClusterIDConflicts counts, per ClusterID, how many times a
+	// conflicting claim from a different remote cluster has been observed.
+	ClusterIDConflicts metric.Vec[metric.Counter]
+
+	// GlobalServiceActiveCluster reports, per global service and remote
+	// cluster, whether that cluster's backends are currently selected by
+	// the service's configured ServicePolicy.
+	GlobalServiceActiveCluster metric.Vec[metric.Gauge]
+}
+
+// NewMetrics returns a new Metrics instance using the default Prometheus
+// registry labels.
+func NewMetrics() Metrics {
+	return Metrics{
+		TotalGlobalServices: metric.NewLegacyGaugeVec(prometheus.GaugeOpts{
+			Namespace: "cilium",
+			Subsystem: "clustermesh",
+			Name:      "global_services",
+			Help:      "The total number of global services in the cluster mesh",
+		}, []string{"source_cluster", "source_node_name"}),
+
+		TotalNodes: metric.NewLegacyGaugeVec(prometheus.GaugeOpts{
+			Namespace: "cilium",
+			Subsystem: "clustermesh",
+			Name:      "remote_clusters_nodes",
+			Help:      "The total number of nodes in the remote cluster",
+		}, []string{"source_cluster", "source_node_name", "target_cluster"}),
+
+		ClusterIDConflicts: metric.NewLegacyCounterVec(prometheus.CounterOpts{
+			Namespace: "cilium",
+			Subsystem: "clustermesh",
+			Name:      "cluster_id_conflicts_total",
+			Help:      "The number of ClusterID conflicts detected between remote clusters",
+		}, []string{"cluster_id"}),
+
+		GlobalServiceActiveCluster: metric.NewLegacyGaugeVec(prometheus.GaugeOpts{
+			Namespace: "cilium",
+			Subsystem: "clustermesh",
+			Name:      "global_service_active_cluster",
+			Help:      "Whether the given remote cluster is currently selected as a backend source for the global service, per its configured merging policy",
+		}, []string{"service", "namespace", "cluster"}),
+	}
+}
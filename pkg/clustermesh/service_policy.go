@@ -0,0 +1,344 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Cilium
+
+package clustermesh
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+// Annotation keys recognized on a global service to select and
+// parameterize its ServicePolicy. They are part of the public contract
+// consumed by ParseServicePolicy, ParseClusterWeights and
+// ParseFailoverPriority, whichever component turns the underlying
+// Kubernetes service's annotations into a merged global service.
+const (
+	// AnnotationGlobalServicePolicy selects the ServicePolicy used to merge
+	// a global service's remote backends. One of "round-robin" (the
+	// default), "local-preferred", "weighted" or "failover".
+	AnnotationGlobalServicePolicy = "service.cilium.io/global-policy"
+
+	// AnnotationServiceWeightPrefix, suffixed with a remote cluster name
+	// (e.g. "service.cilium.io/weight-cluster2"), gives that cluster's
+	// relative weight under ServicePolicyWeighted.
+	AnnotationServiceWeightPrefix = "service.cilium.io/weight-"
+
+	// AnnotationFailoverPriority gives the ordered, comma-separated list of
+	// cluster names tried under ServicePolicyFailover, highest priority
+	// first.
+	AnnotationFailoverPriority = "service.cilium.io/failover-priority"
+)
+
+// ServicePolicy selects how a global service's backends from multiple
+// remote clusters are filtered and weighted before being pushed to the
+// datapath.
+type ServicePolicy string
+
+const (
+	// ServicePolicyRoundRobin is the default policy: every ready backend
+	// from every cluster is included, load-balanced uniformly. This is the
+	// behavior ClusterMesh has always had.
+	ServicePolicyRoundRobin ServicePolicy = "round-robin"
+
+	// ServicePolicyLocalPreferred only includes remote backends when the
+	// local cluster has zero ready backends.
+	ServicePolicyLocalPreferred ServicePolicy = "local-preferred"
+
+	// ServicePolicyWeighted includes every cluster with at least one ready
+	// backend, weighted according to ParseClusterWeights.
+	ServicePolicyWeighted ServicePolicy = "weighted"
+
+	// ServicePolicyFailover only includes the backends of the
+	// highest-priority cluster, per ParseFailoverPriority, that currently
+	// has at least one ready backend.
+	ServicePolicyFailover ServicePolicy = "failover"
+)
+
+// ParseServicePolicy returns the ServicePolicy selected by a service's
+// annotations, defaulting to ServicePolicyRoundRobin when unset or
+// unrecognized.
+func ParseServicePolicy(annotations map[string]string) ServicePolicy {
+	switch ServicePolicy(annotations[AnnotationGlobalServicePolicy]) {
+	case ServicePolicyLocalPreferred:
+		return ServicePolicyLocalPreferred
+	case ServicePolicyWeighted:
+		return ServicePolicyWeighted
+	case ServicePolicyFailover:
+		return ServicePolicyFailover
+	default:
+		return ServicePolicyRoundRobin
+	}
+}
+
+// ParseClusterWeights extracts the per-cluster weights configured via
+// AnnotationServiceWeightPrefix, ignoring clusters with a non-positive or
+// unparsable weight. A cluster absent from the returned map is assumed to
+// have the default weight of 1 by ServicePolicyWeighted.
+func ParseClusterWeights(annotations map[string]string) map[string]int {
+	weights := make(map[string]int)
+	for key, value := range annotations {
+		cluster, ok := strings.CutPrefix(key, AnnotationServiceWeightPrefix)
+		if !ok || cluster == "" {
+			continue
+		}
+		if weight, err := strconv.Atoi(value); err == nil && weight > 0 {
+			weights[cluster] = weight
+		}
+	}
+	return weights
+}
+
+// ParseFailoverPriority extracts the ordered cluster priority list
+// configured via AnnotationFailoverPriority, highest priority first.
+func ParseFailoverPriority(annotations map[string]string) []string {
+	raw := annotations[AnnotationFailoverPriority]
+	if raw == "" {
+		return nil
+	}
+
+	var priority []string
+	for _, cluster := range strings.Split(raw, ",") {
+		if cluster = strings.TrimSpace(cluster); cluster != "" {
+			priority = append(priority, cluster)
+		}
+	}
+	return priority
+}
+
+// ServiceID identifies a global service for policy evaluation purposes.
+type ServiceID struct {
+	Namespace string
+	Name      string
+}
+
+// PolicyDecision is the outcome of evaluating a global service's merging
+// policy: which remote clusters' backends are currently included in the
+// merged backend list, and at what relative weight.
+type PolicyDecision struct {
+	Policy         ServicePolicy
+	ActiveClusters []string
+	Weights        map[string]int
+}
+
+// failoverState tracks, per service, which priority tier (an index into
+// the service's failover priority list) is currently active, and since
+// when a better tier has been the best ready candidate, to implement the
+// failback debounce.
+type failoverState struct {
+	activeTier     int
+	candidateTier  int
+	candidateSince time.Time
+}
+
+// PolicyEvaluator resolves, per global service, which remote clusters'
+// backends are currently included in the merged backend list pushed to
+// the datapath, according to the service's ServicePolicy. It is meant to
+// be invoked wherever the per-cluster ready backend counts returned by
+// globalServiceCache.get() are turned into the list actually handed to
+// the datapath, filtering and weighting that list before it is used.
+//
+// It is safe for concurrent use.
+type PolicyEvaluator struct {
+	localCluster     string
+	failbackDebounce time.Duration
+	metrics          Metrics
+
+	mutex     lock.Mutex
+	failover  map[ServiceID]*failoverState
+	decisions map[ServiceID]PolicyDecision
+}
+
+// NewPolicyEvaluator returns a PolicyEvaluator for the given local cluster
+// name. failbackDebounce is the minimum time a higher-priority cluster
+// must keep reporting ready backends before ServicePolicyFailover fails
+// back to it; zero disables debouncing and fails back immediately.
+func NewPolicyEvaluator(localCluster string, failbackDebounce time.Duration, metrics Metrics) *PolicyEvaluator {
+	return &PolicyEvaluator{
+		localCluster:     localCluster,
+		failbackDebounce: failbackDebounce,
+		metrics:          metrics,
+		failover:         make(map[ServiceID]*failoverState),
+		decisions:        make(map[ServiceID]PolicyDecision),
+	}
+}
+
+// Evaluate filters and weights backendsByCluster (the number of ready
+// backends known for each cluster, including the local cluster, keyed by
+// cluster name) according to policy, weights and priority, returning and
+// caching the resulting PolicyDecision, and updating the
+// GlobalServiceActiveCluster metric.
+func (e *PolicyEvaluator) Evaluate(id ServiceID, policy ServicePolicy, backendsByCluster map[string]int, weights map[string]int, priority []string) PolicyDecision {
+	var decision PolicyDecision
+	switch policy {
+	case ServicePolicyLocalPreferred:
+		decision = e.evaluateLocalPreferred(backendsByCluster)
+	case ServicePolicyWeighted:
+		decision = e.evaluateWeighted(backendsByCluster, weights)
+	case ServicePolicyFailover:
+		decision = e.evaluateFailover(id, backendsByCluster, priority)
+	default:
+		decision = e.evaluateRoundRobin(backendsByCluster)
+	}
+	decision.Policy = policy
+
+	e.mutex.Lock()
+	e.decisions[id] = decision
+	e.mutex.Unlock()
+
+	e.recordMetrics(id, decision, backendsByCluster)
+	return decision
+}
+
+// Decision returns the most recently evaluated PolicyDecision for id, and
+// false if no decision has been recorded for it yet. Recorded decisions are
+// never discarded, so a removed service keeps its last decision. No API
+// endpoint in this package serves it.
+func (e *PolicyEvaluator) Decision(id ServiceID) (PolicyDecision, bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	decision, ok := e.decisions[id]
+	return decision, ok
+}
+
+func (e *PolicyEvaluator) evaluateRoundRobin(backendsByCluster map[string]int) PolicyDecision {
+	weights := make(map[string]int, len(backendsByCluster))
+	var active []string
+	for cluster, count := range backendsByCluster {
+		if count > 0 {
+			active = append(active, cluster)
+			weights[cluster] = 1
+		}
+	}
+	sort.Strings(active)
+	return PolicyDecision{ActiveClusters: active, Weights: weights}
+}
+
+func (e *PolicyEvaluator) evaluateLocalPreferred(backendsByCluster map[string]int) PolicyDecision {
+	if backendsByCluster[e.localCluster] > 0 {
+		return PolicyDecision{
+			ActiveClusters: []string{e.localCluster},
+			Weights:        map[string]int{e.localCluster: 1},
+		}
+	}
+	return e.evaluateRoundRobin(backendsByCluster)
+}
+
+func (e *PolicyEvaluator) evaluateWeighted(backendsByCluster map[string]int, weights map[string]int) PolicyDecision {
+	resolved := make(map[string]int, len(backendsByCluster))
+	var active []string
+	for cluster, count := range backendsByCluster {
+		if count == 0 {
+			continue
+		}
+		weight := weights[cluster]
+		if weight <= 0 {
+			weight = 1
+		}
+		resolved[cluster] = weight
+		active = append(active, cluster)
+	}
+	sort.Strings(active)
+	return PolicyDecision{ActiveClusters: active, Weights: resolved}
+}
+
+func (e *PolicyEvaluator) evaluateFailover(id ServiceID, backendsByCluster map[string]int, priority []string) PolicyDecision {
+	if len(priority) == 0 {
+		return e.evaluateRoundRobin(backendsByCluster)
+	}
+
+	best := firstReadyTier(priority, backendsByCluster)
+	now := time.Now()
+
+	e.mutex.Lock()
+	state, ok := e.failover[id]
+	if !ok {
+		state = &failoverState{activeTier: -1, candidateTier: -1}
+		e.failover[id] = state
+	}
+
+	switch {
+	case best == -1:
+		// Nothing ready anywhere; keep serving the last active tier
+		// rather than emptying the backend list outright.
+	case state.activeTier == -1 || best > state.activeTier:
+		// No active tier yet, or the active tier has degraded further:
+		// fail over to the best ready tier immediately.
+		state.activeTier, state.candidateTier = best, -1
+	case best < state.activeTier:
+		// A higher-priority tier has recovered; only fail back to it once
+		// it has been the best ready tier for the debounce window.
+		if state.candidateTier != best {
+			state.candidateTier, state.candidateSince = best, now
+		}
+		if now.Sub(state.candidateSince) >= e.failbackDebounce {
+			state.activeTier, state.candidateTier = best, -1
+		}
+	default:
+		state.candidateTier = -1
+	}
+
+	activeTier := state.activeTier
+	e.mutex.Unlock()
+
+	if activeTier == -1 {
+		return PolicyDecision{}
+	}
+
+	cluster := priority[activeTier]
+	return PolicyDecision{ActiveClusters: []string{cluster}, Weights: map[string]int{cluster: 1}}
+}
+
+// firstReadyTier returns the index of the highest-priority (lowest index)
+// cluster in priority with at least one ready backend, or -1 if none do.
+func firstReadyTier(priority []string, backendsByCluster map[string]int) int {
+	for i, cluster := range priority {
+		if backendsByCluster[cluster] > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (e *PolicyEvaluator) recordMetrics(id ServiceID, decision PolicyDecision, backendsByCluster map[string]int) {
+	active := make(map[string]bool, len(decision.ActiveClusters))
+	for _, cluster := range decision.ActiveClusters {
+		active[cluster] = true
+	}
+
+	for cluster := range backendsByCluster {
+		value := 0.0
+		if active[cluster] {
+			value = 1
+		}
+		e.metrics.GlobalServiceActiveCluster.WithLabelValues(id.Name, id.Namespace, cluster).Set(value)
+	}
+}
+
+// ExpandWeighted repeats each cluster's backends weight times, in stable
+// cluster order, so that ServicePolicyWeighted's decision can be fed
+// directly into the existing equal-weight load balancer without it having
+// to understand weights itself.
+func ExpandWeighted(backendsByCluster map[string][]string, weights map[string]int) []string {
+	clusters := make([]string, 0, len(backendsByCluster))
+	for cluster := range backendsByCluster {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	var expanded []string
+	for _, cluster := range clusters {
+		weight := weights[cluster]
+		if weight <= 0 {
+			weight = 1
+		}
+		for i := 0; i < weight; i++ {
+			expanded = append(expanded, backendsByCluster[cluster]...)
+		}
+	}
+	return expanded
+}